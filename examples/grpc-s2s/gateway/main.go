@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/envoy/examples/grpc-s2s/service"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	port := flag.Int("port", 8080, "http port")
+	helloAddr := flag.String("hello-addr", "localhost:8081", "address of the hello service")
+	worldAddr := flag.String("world-addr", "localhost:9211", "address of the world service")
+
+	flag.Parse()
+
+	ctx := context.Background()
+	mux := runtime.NewServeMux()
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+
+	if err := service.RegisterHelloHandlerFromEndpoint(ctx, mux, *helloAddr, opts); err != nil {
+		log.Fatalf("failed to register hello handler: %v", err)
+	}
+	if err := service.RegisterWorldHandlerFromEndpoint(ctx, mux, *worldAddr, opts); err != nil {
+		log.Fatalf("failed to register world handler: %v", err)
+	}
+
+	log.Printf("starting grpc-gateway on :%d\n", *port)
+	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", *port), mux))
+}
@@ -5,17 +5,27 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/envoy/examples/grpc-s2s/service"
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	healthz "google.golang.org/grpc/health"
 	healthsvc "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 type helloService struct {
 	service.UnimplementedHelloServer
+	world service.WorldClient
 }
 
 func (s *helloService) Greet(
@@ -23,8 +33,13 @@ func (s *helloService) Greet(
 	in *service.HelloRequest,
 ) (*service.HelloResponse, error) {
 	log.Println("Hello: Received request")
-	// TODO call world service here
-	return &service.HelloResponse{Reply: "hello"}, nil
+
+	worldReply, err := s.world.Greet(ctx, &service.WorldRequest{})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "world service unreachable: %v", err)
+	}
+
+	return &service.HelloResponse{Reply: "hello " + worldReply.Reply}, nil
 }
 
 func updateServiceHealth(
@@ -40,6 +55,9 @@ func updateServiceHealth(
 
 func main() {
 	port := flag.Int("port", 8081, "grpc port")
+	adminPort := flag.Int("admin-port", 8082, "http port serving /healthz, /readyz and /metrics")
+	worldAddr := flag.String("world-addr", "localhost:9211", "address of the world service")
+	drain := flag.Duration("drain", 10*time.Second, "time to wait after marking NOT_SERVING before closing connections")
 
 	flag.Parse()
 
@@ -47,9 +65,28 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	gs := grpc.NewServer()
+	gs := grpc.NewServer(
+		grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
+		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
+	)
 
-	h := helloService{}
+	// healthCheckConfig makes the client-side health-checking balancer watch
+	// the world service's Health/Watch stream and route around it whenever it
+	// reports NOT_SERVING, rather than waiting for RPCs to fail outright.
+	worldConn, err := grpc.Dial(
+		*worldAddr,
+		grpc.WithInsecure(),
+		grpc.WithDefaultServiceConfig(fmt.Sprintf(
+			`{"loadBalancingConfig": [{"round_robin": {}}], "healthCheckConfig": {"serviceName": %q}}`,
+			service.World_ServiceDesc.ServiceName,
+		)),
+	)
+	if err != nil {
+		log.Fatalf("failed to dial world service: %v", err)
+	}
+	defer worldConn.Close()
+
+	h := helloService{world: service.NewWorldClient(worldConn)}
 	service.RegisterHelloServer(gs, &h)
 	reflection.Register(gs)
 
@@ -60,7 +97,56 @@ func main() {
 		service.Hello_ServiceDesc.ServiceName,
 		healthsvc.HealthCheckResponse_SERVING,
 	)
+	grpc_prometheus.Register(gs)
+
+	adminMux := http.NewServeMux()
+	adminMux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	adminMux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		resp, err := healthServer.Check(r.Context(), &healthsvc.HealthCheckRequest{
+			Service: service.Hello_ServiceDesc.ServiceName,
+		})
+		if err != nil || resp.Status != healthsvc.HealthCheckResponse_SERVING {
+			http.Error(w, "not serving", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	adminMux.Handle("/metrics", promhttp.Handler())
+
+	go func() {
+		log.Printf("starting admin http on :%d\n", *adminPort)
+		if err := http.ListenAndServe(fmt.Sprintf(":%d", *adminPort), adminMux); err != nil {
+			log.Fatalf("admin http server stopped: %v", err)
+		}
+	}()
+
+	go func() {
+		log.Printf("starting grpc on :%d\n", *port)
+		if err := gs.Serve(lis); err != nil {
+			log.Fatalf("grpc server stopped: %v", err)
+		}
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	<-sigCh
+
+	log.Println("shutting down: marking services NOT_SERVING")
+	updateServiceHealth(
+		healthServer,
+		service.Hello_ServiceDesc.ServiceName,
+		healthsvc.HealthCheckResponse_NOT_SERVING,
+	)
+	updateServiceHealth(
+		healthServer,
+		"",
+		healthsvc.HealthCheckResponse_NOT_SERVING,
+	)
+
+	log.Printf("draining for %s before closing connections\n", *drain)
+	time.Sleep(*drain)
 
-	log.Printf("starting grpc on :%d\n", *port)
-	gs.Serve(lis)
+	gs.GracefulStop()
 }